@@ -0,0 +1,33 @@
+package config
+
+// ComponentConfig holds settings common to every widget type.
+type ComponentConfig struct {
+	Title  string `yaml:"title"`
+	RateMs int    `yaml:"rate-ms"`
+}
+
+// RunChartConfig represents a run chart widget configuration, as defined in
+// the dashboard YAML file.
+type RunChartConfig struct {
+	ComponentConfig `yaml:",inline"`
+	Precision       *int          `yaml:"precision,omitempty"`
+	Legend          *LegendConfig `yaml:"legend,omitempty"`
+	Percentiles     []float64     `yaml:"percentiles,omitempty"` // e.g. [0.5, 0.95, 0.99]
+	AnomalyK        *float64      `yaml:"anomaly-k,omitempty"`   // std-dev multiplier past which a point is marked anomalous
+	Scale           *ScaleConfig  `yaml:"scale,omitempty"`
+}
+
+// ScaleConfig controls how raw sample values are mapped onto the Y axis.
+type ScaleConfig struct {
+	Type      *string  `yaml:"type,omitempty"` // linear (default) | log10 | symlog
+	LogFloor  *float64 `yaml:"log-floor,omitempty"`
+	LinThresh *float64 `yaml:"lin-thresh,omitempty"`
+}
+
+// LegendConfig controls which summary values are rendered alongside each
+// line's label.
+type LegendConfig struct {
+	Enabled *bool `yaml:"enabled,omitempty"`
+	Details *bool `yaml:"details,omitempty"`
+	Stats   *bool `yaml:"stats,omitempty"` // show mean±σ and configured percentile bands
+}
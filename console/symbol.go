@@ -0,0 +1,8 @@
+package console
+
+const (
+	SymbolSelection     rune = '▲'
+	SymbolVerticalBar   rune = '▎'
+	SymbolHorizontalBar rune = '═'
+	SymbolAnomaly       rune = '✖'
+)
@@ -0,0 +1,93 @@
+package runchart
+
+import (
+	"image"
+	"math"
+	"time"
+
+	ui "github.com/sqshq/termui"
+)
+
+type ChartGrid struct {
+	valueExtrema ValueExtrema
+	timeRange    TimeRange
+	timePerPoint time.Duration
+	minTimeWidth int
+	maxTimeWidth int
+}
+
+func (c *RunChart) newChartGrid() ChartGrid {
+
+	now := time.Now()
+	valueExtrema := ValueExtrema{max: -math.MaxFloat64, min: math.MaxFloat64}
+
+	for _, line := range c.lines {
+		if line.extrema.max > valueExtrema.max {
+			valueExtrema.max = line.extrema.max
+		}
+		if line.extrema.min < valueExtrema.min {
+			valueExtrema.min = line.extrema.min
+		}
+	}
+
+	if valueExtrema.max == -math.MaxFloat64 {
+		valueExtrema = ValueExtrema{max: 0, min: 0}
+	}
+
+	minTimeWidth := xAxisLabelsIndent + c.getMaxValueLength(valueExtrema) + yAxisLabelsIndent
+	maxTimeWidth := c.Inner.Max.X
+
+	gridCount := (c.Inner.Dx() - minTimeWidth) / xAxisGridWidth
+	if gridCount == 0 {
+		gridCount = 1
+	}
+
+	return ChartGrid{
+		valueExtrema: valueExtrema,
+		timeRange:    TimeRange{max: now, min: now.Add(-c.timescale * time.Duration(gridCount))},
+		timePerPoint: c.timescale / xAxisGridWidth,
+		minTimeWidth: minTimeWidth,
+		maxTimeWidth: maxTimeWidth,
+	}
+}
+
+func (c *RunChart) renderAxes(buffer *ui.Buffer) {
+
+	// draw y axis value labels, generating ticks in scale space so a log
+	// axis reads e.g. 1, 10, 100 instead of a linear split of raw values
+	labelsCount := (c.Inner.Dy() - xAxisLabelsHeight - 1) / (yAxisLabelsIndent + yAxisLabelsHeight)
+	scaledMin, scaledMax := c.toScaleSpace(c.grid.valueExtrema.min), c.toScaleSpace(c.grid.valueExtrema.max)
+	scaledValuePerLabel := (scaledMax - scaledMin) / float64(labelsCount)
+
+	for i := 0; i <= labelsCount; i++ {
+		value := c.fromScaleSpace(scaledMax - scaledValuePerLabel*float64(i))
+		y := c.Inner.Min.Y + i*(yAxisLabelsIndent+yAxisLabelsHeight)
+		buffer.SetString(
+			c.formatValue(value, c.grid.valueExtrema),
+			ui.NewStyle(ui.ColorWhite),
+			image.Pt(c.Inner.Min.X, y))
+	}
+
+	// draw y axis line
+	for y := c.Inner.Min.Y; y < c.Inner.Max.Y-xAxisLabelsHeight-1; y++ {
+		buffer.SetCell(
+			ui.NewCell(ui.VERTICAL_DASH, ui.NewStyle(ui.ColorWhite)),
+			image.Pt(c.Inner.Min.X+c.grid.minTimeWidth, y))
+	}
+
+	// draw x axis line and grid lines
+	for x := c.grid.minTimeWidth + 1; x < c.Inner.Dx(); x++ {
+		buffer.SetCell(
+			ui.NewCell(ui.HORIZONTAL_DASH, ui.NewStyle(ui.ColorWhite)),
+			image.Pt(x+c.Inner.Min.X, c.Inner.Max.Y-xAxisLabelsHeight-1))
+	}
+
+	// draw x axis time labels
+	for x := c.grid.minTimeWidth + xAxisGridWidth; x <= c.grid.maxTimeWidth-c.Inner.Min.X; x += xAxisGridWidth {
+		labelTime := c.grid.timeRange.min.Add(c.timescale * time.Duration((x-c.grid.minTimeWidth)/xAxisGridWidth))
+		buffer.SetString(
+			labelTime.Format("15:04:05"),
+			ui.NewStyle(ui.ColorWhite),
+			image.Pt(x+c.Inner.Min.X-xAxisLabelsWidth/2, c.Inner.Max.Y-xAxisLabelsHeight))
+	}
+}
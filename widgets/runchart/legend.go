@@ -0,0 +1,57 @@
+package runchart
+
+import (
+	"fmt"
+	"image"
+
+	ui "github.com/sqshq/termui"
+)
+
+// Legend controls which summary values are rendered alongside each line label.
+type Legend struct {
+	Enabled bool
+	Details bool
+	Stats   bool // show mean±σ and configured percentile bands
+}
+
+func (c *RunChart) renderLegend(buffer *ui.Buffer, drawArea image.Rectangle) {
+
+	if !c.legend.Enabled || len(c.lines) == 0 {
+		return
+	}
+
+	point := image.Pt(drawArea.Min.X, drawArea.Min.Y)
+
+	for _, line := range c.lines {
+
+		label := line.label
+
+		if c.legend.Details {
+			label = label + " " + c.formatLegendDetails(line)
+		}
+
+		buffer.SetString(label, ui.NewStyle(line.color), point)
+		point = image.Pt(drawArea.Min.X, point.Y+1)
+	}
+}
+
+func (c *RunChart) formatLegendDetails(line TimeLine) string {
+
+	extrema := c.grid.valueExtrema
+
+	details := "min: " + c.formatValue(line.extrema.min, extrema) +
+		" max: " + c.formatValue(line.extrema.max, extrema)
+
+	if c.legend.Stats && line.stats.count > 0 {
+		details += " mean: " + c.formatValue(line.stats.Mean(), extrema) +
+			"±" + c.formatValue(line.stats.StdDev(), extrema)
+
+		for _, p := range c.percentiles {
+			if value, ok := line.stats.Percentile(p); ok {
+				details += fmt.Sprintf(" p%d: %s", int(p*100), c.formatValue(value, extrema))
+			}
+		}
+	}
+
+	return details
+}
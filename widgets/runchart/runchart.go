@@ -1,7 +1,7 @@
 package runchart
 
 import (
-	"fmt"
+	"github.com/sqshq/sampler/config"
 	"github.com/sqshq/sampler/console"
 	"github.com/sqshq/sampler/data"
 	"image"
@@ -13,6 +13,11 @@ import (
 	ui "github.com/sqshq/termui"
 )
 
+const (
+	defaultPrecision = 2
+	defaultAnomalyK  = 3.0
+)
+
 const (
 	xAxisLabelsHeight = 1
 	xAxisLabelsWidth  = 8
@@ -36,14 +41,17 @@ const (
 
 type RunChart struct {
 	ui.Block
-	lines     []TimeLine
-	grid      ChartGrid
-	timescale time.Duration
-	mutex     *sync.Mutex
-	mode      Mode
-	selection time.Time
-	precision int
-	legend    Legend
+	lines       []TimeLine
+	grid        ChartGrid
+	timescale   time.Duration
+	mutex       *sync.Mutex
+	mode        Mode
+	selection   time.Time
+	precision   int
+	legend      Legend
+	percentiles []float64
+	anomalyK    float64
+	scaleConfig ScaleConfig
 }
 
 type TimePoint struct {
@@ -59,6 +67,7 @@ type TimeLine struct {
 	label               string
 	selectionCoordinate int
 	selectionPoint      TimePoint
+	stats               LineStats
 }
 
 type TimeRange struct {
@@ -71,17 +80,62 @@ type ValueExtrema struct {
 	min float64
 }
 
-func NewRunChart(title string, precision int, refreshRateMs int, legend Legend) *RunChart {
+func NewRunChart(cfg config.RunChartConfig, refreshRateMs int) *RunChart {
 	block := *ui.NewBlock()
-	block.Title = title
+	block.Title = cfg.Title
+
+	precision := defaultPrecision
+	if cfg.Precision != nil {
+		precision = *cfg.Precision
+	}
+
+	anomalyK := defaultAnomalyK
+	if cfg.AnomalyK != nil {
+		anomalyK = *cfg.AnomalyK
+	}
+
+	legend := Legend{Enabled: true, Details: true}
+	if cfg.Legend != nil {
+		if cfg.Legend.Enabled != nil {
+			legend.Enabled = *cfg.Legend.Enabled
+		}
+		if cfg.Legend.Details != nil {
+			legend.Details = *cfg.Legend.Details
+		}
+		if cfg.Legend.Stats != nil {
+			legend.Stats = *cfg.Legend.Stats
+		}
+	}
+
+	scaleConfig := ScaleConfig{Scale: ScaleLinear}
+	if cfg.Scale != nil {
+		if cfg.Scale.Type != nil {
+			switch *cfg.Scale.Type {
+			case "log10":
+				scaleConfig.Scale = ScaleLog10
+			case "symlog":
+				scaleConfig.Scale = ScaleSymLog
+			}
+		}
+		if cfg.Scale.LogFloor != nil {
+			scaleConfig.LogFloor = *cfg.Scale.LogFloor
+		}
+		if cfg.Scale.LinThresh != nil {
+			scaleConfig.LinThresh = *cfg.Scale.LinThresh
+		}
+	}
+
 	return &RunChart{
-		Block:     block,
-		lines:     []TimeLine{},
-		timescale: calculateTimescale(refreshRateMs),
-		mutex:     &sync.Mutex{},
-		precision: precision,
-		mode:      ModeDefault,
-		legend:    legend,
+		Block:       block,
+		lines:       []TimeLine{},
+		timescale:   calculateTimescale(refreshRateMs),
+		mutex:       &sync.Mutex{},
+		precision:   precision,
+		mode:        ModeDefault,
+		legend:      legend,
+		percentiles: cfg.Percentiles,
+		anomalyK:    anomalyK,
+		scaleConfig: scaleConfig,
 	}
 }
 
@@ -117,6 +171,7 @@ func (c *RunChart) AddLine(Label string, color ui.Color) {
 		color:   color,
 		label:   Label,
 		extrema: ValueExtrema{max: -math.MaxFloat64, min: math.MaxFloat64},
+		stats:   newLineStats(c.percentiles),
 	}
 	c.lines = append(c.lines, line)
 }
@@ -147,6 +202,7 @@ func (c *RunChart) ConsumeSample(sample data.Sample) {
 		line.extrema.max = float
 	}
 
+	line.stats.Add(float)
 	line.points = append(line.points, c.newTimePoint(float))
 	c.lines[index] = line
 
@@ -169,6 +225,7 @@ func (c *RunChart) renderLines(buffer *ui.Buffer, drawArea image.Rectangle) {
 
 	selectionCoordinate := c.calculateTimeCoordinate(c.selection)
 	selectionPoints := make(map[int]image.Point)
+	anomalyPoints := make(map[int][]image.Point)
 
 	probe := c.lines[0].points[0]
 	delta := ui.AbsInt(c.calculateTimeCoordinate(probe.time) - probe.coordinate)
@@ -189,14 +246,7 @@ func (c *RunChart) renderLines(buffer *ui.Buffer, drawArea image.Rectangle) {
 			timePoint.coordinate -= delta
 			line.points[j] = timePoint
 
-			var y int
-			if c.grid.valueExtrema.max == c.grid.valueExtrema.min {
-				y = (drawArea.Dy() - 2) / 2
-			} else {
-				valuePerY := (c.grid.valueExtrema.max - c.grid.valueExtrema.min) / float64(drawArea.Dy()-2)
-				y = int(float64(timePoint.value-c.grid.valueExtrema.min) / valuePerY)
-			}
-
+			y := c.valueY(timePoint.value, drawArea)
 			point := image.Pt(timePoint.coordinate, drawArea.Max.Y-y-1)
 
 			if _, exists := xPoint[point.X]; exists {
@@ -217,6 +267,10 @@ func (c *RunChart) renderLines(buffer *ui.Buffer, drawArea image.Rectangle) {
 				selectionPoints[i] = point
 			}
 
+			if line.stats.IsAnomaly(timePoint.value, c.anomalyK) {
+				anomalyPoints[i] = append(anomalyPoints[i], point)
+			}
+
 			xPoint[point.X] = point
 			xOrder = append(xOrder, point.X)
 		}
@@ -240,6 +294,10 @@ func (c *RunChart) renderLines(buffer *ui.Buffer, drawArea image.Rectangle) {
 		}
 	}
 
+	// draw the percentile reference lines first, so the data, selection and
+	// anomaly layers drawn on top of it are never hidden by it
+	c.renderPercentileLines(buffer, drawArea)
+
 	canvas.Draw(buffer)
 
 	if c.mode == ModePinpoint {
@@ -250,6 +308,50 @@ func (c *RunChart) renderLines(buffer *ui.Buffer, drawArea image.Rectangle) {
 			}
 		}
 	}
+
+	for lineIndex, points := range anomalyPoints {
+		for _, point := range points {
+			buffer.SetCell(ui.NewCell(console.SymbolAnomaly, ui.NewStyle(c.lines[lineIndex].color)), point)
+		}
+	}
+}
+
+// renderPercentileLines draws a horizontal reference line at each
+// configured percentile, using the P² estimate maintained in line.stats
+// instead of scanning a sorted buffer of points.
+func (c *RunChart) renderPercentileLines(buffer *ui.Buffer, drawArea image.Rectangle) {
+
+	for _, line := range c.lines {
+		for _, p := range c.percentiles {
+
+			value, ok := line.stats.Percentile(p)
+			if !ok {
+				continue
+			}
+
+			row := drawArea.Max.Y - c.valueY(value, drawArea) - 1
+			if row < drawArea.Min.Y || row >= drawArea.Max.Y {
+				continue
+			}
+
+			for x := drawArea.Min.X; x < drawArea.Max.X; x++ {
+				buffer.SetCell(ui.NewCell(console.SymbolHorizontalBar, ui.NewStyle(line.color)), image.Pt(x, row))
+			}
+		}
+	}
+}
+
+// valueY maps value to a Y offset within drawArea, honoring the chart's
+// configured scale.
+func (c *RunChart) valueY(value float64, drawArea image.Rectangle) int {
+
+	scaledMin, scaledMax := c.toScaleSpace(c.grid.valueExtrema.min), c.toScaleSpace(c.grid.valueExtrema.max)
+	if scaledMax == scaledMin {
+		return (drawArea.Dy() - 2) / 2
+	}
+
+	valuePerY := (scaledMax - scaledMin) / float64(drawArea.Dy()-2)
+	return int((c.toScaleSpace(value) - scaledMin) / valuePerY)
 }
 
 func (c *RunChart) trimOutOfRangeValues() {
@@ -266,7 +368,17 @@ func (c *RunChart) trimOutOfRangeValues() {
 		}
 
 		if lastOutOfRangeValueIndex > 0 {
+			for _, point := range item.points[:lastOutOfRangeValueIndex+1] {
+				item.stats.Remove(point.value)
+			}
 			item.points = append(item.points[:0], item.points[lastOutOfRangeValueIndex+1:]...)
+
+			retained := make([]float64, len(item.points))
+			for j, point := range item.points {
+				retained[j] = point.value
+			}
+			item.stats.RebuildPercentiles(retained)
+
 			c.lines[i] = item
 		}
 	}
@@ -279,13 +391,13 @@ func (c *RunChart) calculateTimeCoordinate(t time.Time) int {
 }
 
 // TODO add boundaries for values in range
-func (c *RunChart) getMaxValueLength() int {
+func (c *RunChart) getMaxValueLength(extrema ValueExtrema) int {
 
 	maxValueLength := 0
 
 	for _, line := range c.lines {
 		for _, point := range line.points {
-			l := len(formatValue(point.value, c.precision))
+			l := len(c.formatValue(point.value, extrema))
 			if l > maxValueLength {
 				maxValueLength = l
 			}
@@ -327,15 +439,6 @@ func getMidRangeTime(r TimeRange) time.Time {
 	return r.max.Add(-delta / 2)
 }
 
-func formatValue(value float64, precision int) string {
-	if math.Abs(value) == math.MaxFloat64 {
-		return "Inf"
-	} else {
-		format := "%." + strconv.Itoa(precision) + "f"
-		return fmt.Sprintf(format, value)
-	}
-}
-
 // time duration between grid lines
 func calculateTimescale(refreshRateMs int) time.Duration {
 
@@ -0,0 +1,97 @@
+package runchart
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+type Scale int
+
+const (
+	ScaleLinear Scale = 0
+	ScaleLog10  Scale = 1
+	ScaleSymLog Scale = 2
+)
+
+const (
+	defaultLogFloor  = 1e-9
+	defaultLinThresh = 1
+	// values spanning more decades than this render with scientific notation
+	scientificNotationDecades = 4
+)
+
+// ScaleConfig controls how raw sample values are mapped onto the Y axis.
+type ScaleConfig struct {
+	Scale     Scale
+	LogFloor  float64 // values below this are clamped before Log10 is applied
+	LinThresh float64 // linear-to-log transition point used by SymLog
+}
+
+// toScaleSpace maps a raw value into the space the Y axis is laid out in, so
+// renderLines and the axis tick generator can interpolate linearly afterwards.
+func (c *RunChart) toScaleSpace(value float64) float64 {
+	switch c.scaleConfig.Scale {
+	case ScaleLog10:
+		floor := c.scaleConfig.LogFloor
+		if floor <= 0 {
+			floor = defaultLogFloor
+		}
+		if value < floor {
+			value = floor
+		}
+		return math.Log10(value)
+	case ScaleSymLog:
+		linThresh := c.scaleConfig.LinThresh
+		if linThresh <= 0 {
+			linThresh = defaultLinThresh
+		}
+		return math.Copysign(math.Log10(1+math.Abs(value)/linThresh), value)
+	default:
+		return value
+	}
+}
+
+// fromScaleSpace inverts toScaleSpace, used to label Y axis ticks with
+// values in the chart's own scale.
+func (c *RunChart) fromScaleSpace(value float64) float64 {
+	switch c.scaleConfig.Scale {
+	case ScaleLog10:
+		return math.Pow(10, value)
+	case ScaleSymLog:
+		linThresh := c.scaleConfig.LinThresh
+		if linThresh <= 0 {
+			linThresh = defaultLinThresh
+		}
+		return math.Copysign(linThresh*(math.Pow(10, math.Abs(value))-1), value)
+	default:
+		return value
+	}
+}
+
+// usesScientificNotation reports whether extrema spans enough decades that
+// fixed-point formatting would be unreadable. It takes extrema explicitly
+// rather than reading c.grid, since callers computing a fresh grid (e.g.
+// newChartGrid, via getMaxValueLength) must format against the extrema they
+// just computed locally, not the previous frame's c.grid.valueExtrema.
+func usesScientificNotation(extrema ValueExtrema) bool {
+
+	if extrema.min <= 0 || extrema.max <= 0 {
+		return false
+	}
+
+	return math.Log10(extrema.max/extrema.min) > scientificNotationDecades
+}
+
+func (c *RunChart) formatValue(value float64, extrema ValueExtrema) string {
+
+	if math.Abs(value) == math.MaxFloat64 {
+		return "Inf"
+	}
+
+	if usesScientificNotation(extrema) {
+		return strconv.FormatFloat(value, 'e', c.precision, 64)
+	}
+
+	return fmt.Sprintf("%.*f", c.precision, value)
+}
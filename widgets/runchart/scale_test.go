@@ -0,0 +1,52 @@
+package runchart
+
+import "testing"
+
+func TestToScaleSpaceLinearIsIdentity(t *testing.T) {
+	c := &RunChart{scaleConfig: ScaleConfig{Scale: ScaleLinear}}
+	if got := c.toScaleSpace(42.5); got != 42.5 {
+		t.Fatalf("expected linear scale to be an identity transform, got %f", got)
+	}
+}
+
+func TestToScaleSpaceLog10ClampsNonPositiveValues(t *testing.T) {
+	c := &RunChart{scaleConfig: ScaleConfig{Scale: ScaleLog10, LogFloor: 1}}
+	if got := c.toScaleSpace(0); got != 0 {
+		t.Fatalf("expected value clamped to floor 1 to map to log10(1)=0, got %f", got)
+	}
+	if got := c.toScaleSpace(100); got != 2 {
+		t.Fatalf("expected log10(100)=2, got %f", got)
+	}
+}
+
+func TestScaleSpaceRoundTrip(t *testing.T) {
+
+	cases := []struct {
+		config ScaleConfig
+		values []float64
+	}{
+		{ScaleConfig{Scale: ScaleLinear}, []float64{-500, -1, 0, 1, 500}},
+		{ScaleConfig{Scale: ScaleLog10, LogFloor: 1e-9}, []float64{1, 10, 500}},
+		{ScaleConfig{Scale: ScaleSymLog, LinThresh: 10}, []float64{-500, -1, 0, 1, 500}},
+	}
+
+	for _, tc := range cases {
+		c := &RunChart{scaleConfig: tc.config}
+		for _, value := range tc.values {
+			got := c.fromScaleSpace(c.toScaleSpace(value))
+			if diff := got - value; diff > 1e-6 || diff < -1e-6 {
+				t.Errorf("scale %v: round trip of %f produced %f", tc.config.Scale, value, got)
+			}
+		}
+	}
+}
+
+func TestSymLogPreservesSign(t *testing.T) {
+	c := &RunChart{scaleConfig: ScaleConfig{Scale: ScaleSymLog, LinThresh: 1}}
+	if c.toScaleSpace(-50) >= 0 {
+		t.Fatal("expected symlog to preserve the sign of negative values")
+	}
+	if c.toScaleSpace(50) <= 0 {
+		t.Fatal("expected symlog to preserve the sign of positive values")
+	}
+}
@@ -0,0 +1,211 @@
+package runchart
+
+import (
+	"math"
+	"sort"
+)
+
+// LineStats maintains per-line running statistics without rescanning
+// points on every sample. Mean and standard deviation are tracked with
+// Welford's online algorithm, and each configured percentile is tracked
+// with the P² algorithm (Jain & Chlamtac), which estimates a quantile
+// from a fixed set of five markers instead of a sorted buffer.
+type LineStats struct {
+	count       int64
+	mean        float64
+	m2          float64
+	percentiles map[float64]*p2Estimator
+}
+
+func newLineStats(percentiles []float64) LineStats {
+	estimators := make(map[float64]*p2Estimator, len(percentiles))
+	for _, p := range percentiles {
+		estimators[p] = newP2Estimator(p)
+	}
+	return LineStats{percentiles: estimators}
+}
+
+// Add folds value into the running mean/variance and every configured
+// percentile estimator in O(1).
+func (s *LineStats) Add(value float64) {
+
+	s.count++
+	delta := value - s.mean
+	s.mean += delta / float64(s.count)
+	s.m2 += delta * (value - s.mean)
+
+	for _, e := range s.percentiles {
+		e.Add(value)
+	}
+}
+
+// Remove reverses the contribution of value to the mean/variance. It does
+// not touch the percentile estimators -- see RebuildPercentiles, which the
+// caller invokes once after a whole batch of points has been trimmed.
+func (s *LineStats) Remove(value float64) {
+
+	if s.count <= 1 {
+		s.count, s.mean, s.m2 = 0, 0, 0
+		return
+	}
+
+	oldMean := s.mean
+	s.count--
+	s.mean = (oldMean*float64(s.count+1) - value) / float64(s.count)
+	s.m2 -= (value - oldMean) * (value - s.mean)
+	if s.m2 < 0 {
+		s.m2 = 0
+	}
+}
+
+// RebuildPercentiles resets every configured percentile estimator and
+// replays values into it. The P² markers have no incremental reverse
+// formula, so after a batch of points is trimmed the estimators are rebuilt
+// from the points that remain, rather than relearning from scratch as new
+// live samples trickle in.
+func (s *LineStats) RebuildPercentiles(values []float64) {
+
+	for p, e := range s.percentiles {
+		s.percentiles[p] = newP2Estimator(e.p)
+	}
+
+	for _, value := range values {
+		for _, e := range s.percentiles {
+			e.Add(value)
+		}
+	}
+}
+
+func (s *LineStats) Mean() float64 {
+	return s.mean
+}
+
+func (s *LineStats) StdDev() float64 {
+	if s.count < 2 {
+		return 0
+	}
+	return math.Sqrt(s.m2 / float64(s.count-1))
+}
+
+// Percentile reports the current estimate for p, and whether enough
+// samples have been observed to trust it.
+func (s *LineStats) Percentile(p float64) (float64, bool) {
+	e, ok := s.percentiles[p]
+	if !ok {
+		return 0, false
+	}
+	return e.Value(), e.n >= 5
+}
+
+// IsAnomaly reports whether value lies more than k standard deviations
+// away from the running mean.
+func (s *LineStats) IsAnomaly(value, k float64) bool {
+	if s.count < 2 || k <= 0 {
+		return false
+	}
+	return math.Abs(value-s.mean) > k*s.StdDev()
+}
+
+// p2Estimator implements the P² algorithm for estimating quantile p from
+// a stream, using 5 markers (min, three quantile markers, max) instead of
+// a sorted buffer.
+type p2Estimator struct {
+	p       float64
+	n       int
+	q       [5]float64
+	pos     [5]int
+	desired [5]float64
+	dn      [5]float64
+}
+
+func newP2Estimator(p float64) *p2Estimator {
+	return &p2Estimator{p: p}
+}
+
+func (e *p2Estimator) Add(value float64) {
+
+	if e.n < 5 {
+		e.q[e.n] = value
+		e.n++
+		if e.n == 5 {
+			sort.Float64s(e.q[:])
+			for i := range e.pos {
+				e.pos[i] = i + 1
+			}
+			e.desired = [5]float64{1, 1 + 2*e.p, 1 + 4*e.p, 3 + 2*e.p, 5}
+			e.dn = [5]float64{0, e.p / 2, e.p, (1 + e.p) / 2, 1}
+		}
+		return
+	}
+
+	k := 0
+	switch {
+	case value < e.q[0]:
+		e.q[0] = value
+	case value >= e.q[4]:
+		e.q[4] = value
+		k = 3
+	default:
+		for i := 0; i < 4; i++ {
+			if value < e.q[i+1] {
+				k = i
+				break
+			}
+		}
+	}
+
+	for i := k + 1; i < 5; i++ {
+		e.pos[i]++
+	}
+	for i := range e.desired {
+		e.desired[i] += e.dn[i]
+	}
+
+	for i := 1; i < 4; i++ {
+
+		d := e.desired[i] - float64(e.pos[i])
+		sign := 0
+		if d >= 1 && e.pos[i+1]-e.pos[i] > 1 {
+			sign = 1
+		} else if d <= -1 && e.pos[i-1]-e.pos[i] < -1 {
+			sign = -1
+		}
+		if sign == 0 {
+			continue
+		}
+
+		qNew := e.parabolic(i, float64(sign))
+		if e.q[i-1] < qNew && qNew < e.q[i+1] {
+			e.q[i] = qNew
+		} else {
+			e.q[i] = e.linear(i, sign)
+		}
+		e.pos[i] += sign
+	}
+
+	e.n++
+}
+
+func (e *p2Estimator) parabolic(i int, d float64) float64 {
+	left := (float64(e.pos[i]-e.pos[i-1]) + d) * (e.q[i+1] - e.q[i]) / float64(e.pos[i+1]-e.pos[i])
+	right := (float64(e.pos[i+1]-e.pos[i]) - d) * (e.q[i] - e.q[i-1]) / float64(e.pos[i]-e.pos[i-1])
+	return e.q[i] + d/float64(e.pos[i+1]-e.pos[i-1])*(left+right)
+}
+
+func (e *p2Estimator) linear(i int, d int) float64 {
+	return e.q[i] + float64(d)*(e.q[i+d]-e.q[i])/float64(e.pos[i+d]-e.pos[i])
+}
+
+// Value returns the current estimate of the p-th quantile.
+func (e *p2Estimator) Value() float64 {
+	if e.n < 5 {
+		sorted := append([]float64{}, e.q[:e.n]...)
+		sort.Float64s(sorted)
+		if len(sorted) == 0 {
+			return 0
+		}
+		idx := int(e.p * float64(len(sorted)-1))
+		return sorted[idx]
+	}
+	return e.q[2]
+}
@@ -0,0 +1,96 @@
+package runchart
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestLineStatsMeanAndStdDev(t *testing.T) {
+
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	stats := newLineStats(nil)
+
+	for _, v := range values {
+		stats.Add(v)
+	}
+
+	if math.Abs(stats.Mean()-5) > 1e-9 {
+		t.Fatalf("expected mean 5, got %f", stats.Mean())
+	}
+
+	if math.Abs(stats.StdDev()-2.138089935) > 1e-6 {
+		t.Fatalf("expected stddev ~2.138089935, got %f", stats.StdDev())
+	}
+}
+
+func TestLineStatsRemoveRebasesMeanAndStdDev(t *testing.T) {
+
+	values := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	stats := newLineStats(nil)
+
+	for _, v := range values {
+		stats.Add(v)
+	}
+
+	stats.Remove(values[0])
+
+	expected := newLineStats(nil)
+	for _, v := range values[1:] {
+		expected.Add(v)
+	}
+
+	if math.Abs(stats.Mean()-expected.Mean()) > 1e-9 {
+		t.Fatalf("expected mean %f after remove, got %f", expected.Mean(), stats.Mean())
+	}
+
+	if math.Abs(stats.StdDev()-expected.StdDev()) > 1e-6 {
+		t.Fatalf("expected stddev %f after remove, got %f", expected.StdDev(), stats.StdDev())
+	}
+}
+
+func TestLineStatsPercentileConvergesOnSortedReference(t *testing.T) {
+
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, 2000)
+	for i := range values {
+		values[i] = r.Float64() * 1000
+	}
+
+	stats := newLineStats([]float64{0.5, 0.95, 0.99})
+	for _, v := range values {
+		stats.Add(v)
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	for _, p := range []float64{0.5, 0.95, 0.99} {
+		got, ok := stats.Percentile(p)
+		if !ok {
+			t.Fatalf("expected percentile p%d to be ready", int(p*100))
+		}
+		want := sorted[int(p*float64(len(sorted)-1))]
+		if math.Abs(got-want)/want > 0.1 {
+			t.Errorf("p%d estimate %f too far from reference %f", int(p*100), got, want)
+		}
+	}
+}
+
+func TestLineStatsIsAnomaly(t *testing.T) {
+
+	stats := newLineStats(nil)
+	for i := 0; i < 100; i++ {
+		stats.Add(10)
+	}
+
+	if stats.IsAnomaly(10, 3) {
+		t.Fatal("value equal to the mean should never be an anomaly")
+	}
+
+	stats.Add(11)
+	if !stats.IsAnomaly(1000, 3) {
+		t.Fatal("expected a far outlier to be flagged as an anomaly")
+	}
+}